@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// requireHostSecret skips the test if getHostSecret fails on this machine
+// (e.g. the secure path can't be created, or on Linux neither secret-tool
+// nor the host_secret file fallback works), since Encrypt/Decrypt derive
+// their key from whatever getHostSecret returns.
+func requireHostSecret(t *testing.T) {
+	t.Helper()
+	if _, err := getHostSecret(); err != nil {
+		t.Skipf("no OS keystore available on this host: %v", err)
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	requireHostSecret(t)
+
+	const plaintext = "super-secret-access-token"
+	blob, err := Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	got, migrated, err := Decrypt(blob)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if migrated {
+		t.Error("Decrypt reported migrated=true for a blob that was never legacy XOR")
+	}
+	if got != plaintext {
+		t.Errorf("Decrypt round-trip = %q, want %q", got, plaintext)
+	}
+}
+
+func TestDecryptMigratesLegacyXOR(t *testing.T) {
+	requireHostSecret(t)
+
+	// Reproduce a legacy blob: raw repeating-key XOR bytes, no version prefix.
+	const plaintext = "legacy-plaintext-token"
+	extendedKey := extendKeyLegacy([]byte(ENCRYPTION_KEY), len(plaintext))
+	legacy := make([]byte, len(plaintext))
+	for i := range legacy {
+		legacy[i] = plaintext[i] ^ extendedKey[i]
+	}
+
+	plain, migrated, err := Decrypt(base64.StdEncoding.EncodeToString(legacy))
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !migrated {
+		t.Error("Decrypt did not report migrated=true for a legacy XOR blob")
+	}
+	if plain != plaintext {
+		t.Errorf("Decrypt(legacy) = %q, want %q", plain, plaintext)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/satyam235/macos-notifier/SecOpsRebootNotifier/rpc"
+)
+
+// grpcLoopbackAddr is where NotifierService listens for TCP callers. Only
+// loopback, so reaching it from another host still requires an SSH tunnel
+// or similar; the Unix socket below is the intended local IPC path. On
+// Windows, where there's no Unix socket fallback, this TCP listener is the
+// only control path, which is exactly why every RPC on it also requires
+// the bearer token grpcAuthInterceptors checks.
+const grpcLoopbackAddr = "127.0.0.1:51987"
+
+// grpcTokenFileName holds the random per-run bearer token gRPC callers must
+// present, written 0600 next to the Unix socket so reaching either listener
+// requires the same local privilege level that editing
+// SecOpsNotifierConfig.json already did.
+const grpcTokenFileName = "secops_notifier.token"
+
+// notifierGRPCServer implements rpc.NotifierServiceServer on top of the
+// existing config-file mutators (scheduleTask/scheduleRebootNowTask/
+// deleteScheduledTask/updateConfig), so an orchestrator can drive the
+// notifier over gRPC instead of writing SecOpsNotifierConfig.json directly.
+type notifierGRPCServer struct {
+	rpc.UnimplementedNotifierServiceServer
+}
+
+func (s *notifierGRPCServer) ScheduleReboot(ctx context.Context, req *rpc.ScheduleRebootRequest) (*rpc.ScheduleRebootResponse, error) {
+	updates := map[string]interface{}{
+		"reboot_now":     req.DelaySeconds <= 0,
+		"task_scheduled": false,
+	}
+	if req.Message != "" {
+		updates["custom_message"] = req.Message
+	}
+	if req.DelaySeconds > 0 {
+		at := time.Now().Add(time.Duration(req.DelaySeconds) * time.Second)
+		updates["scheduled_time"] = at.Format("2006-01-02 15:04:05")
+	}
+
+	if err := updateConfig(updates); err != nil {
+		return &rpc.ScheduleRebootResponse{Error: err.Error()}, nil
+	}
+	return &rpc.ScheduleRebootResponse{Accepted: true}, nil
+}
+
+func (s *notifierGRPCServer) CancelReboot(ctx context.Context, req *rpc.CancelRebootRequest) (*rpc.CancelRebootResponse, error) {
+	if err := deleteScheduledTask(); err != nil {
+		return &rpc.CancelRebootResponse{Error: err.Error()}, nil
+	}
+	if err := updateConfig(map[string]interface{}{"task_scheduled": false, "reboot_now": false}); err != nil {
+		return &rpc.CancelRebootResponse{Error: err.Error()}, nil
+	}
+	return &rpc.CancelRebootResponse{Accepted: true}, nil
+}
+
+func (s *notifierGRPCServer) TriggerPatchScan(ctx context.Context, req *rpc.TriggerPatchScanRequest) (*rpc.TriggerPatchScanResponse, error) {
+	if err := patchScan(&SECOPS_NOTIFIER_CONFIG); err != nil {
+		return &rpc.TriggerPatchScanResponse{Error: err.Error()}, nil
+	}
+	return &rpc.TriggerPatchScanResponse{Accepted: true}, nil
+}
+
+func (s *notifierGRPCServer) GetStatus(ctx context.Context, req *rpc.GetStatusRequest) (*rpc.StatusResponse, error) {
+	return currentStatus(), nil
+}
+
+func (s *notifierGRPCServer) WatchStatus(req *rpc.WatchStatusRequest, stream rpc.NotifierService_WatchStatusServer) error {
+	if err := stream.Send(currentStatus()); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-ticker.C:
+			if err := stream.Send(currentStatus()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// currentStatus snapshots the fields an orchestrator needs, the gRPC
+// equivalent of what used to only be visible by reading the config file and
+// tailing the log.
+func currentStatus() *rpc.StatusResponse {
+	return &rpc.StatusResponse{
+		RebootRequired:   LAST_REBOOT_STATUS.Required,
+		Reasons:          LAST_REBOOT_STATUS.Reasons,
+		Packages:         LAST_REBOOT_STATUS.Packages,
+		RebootScheduled:  SECOPS_NOTIFIER_CONFIG.TaskScheduled,
+		ScheduledTime:    SECOPS_NOTIFIER_CONFIG.ScheduledTime,
+		RunningPatchTask: checkPatchTaskProcess(),
+	}
+}
+
+// generateGRPCToken creates a random bearer token for this run and writes it
+// 0600-permissioned to securePath, so only whoever can already read that
+// directory (the same bar writing SecOpsNotifierConfig.json requires) can
+// retrieve it and call the gRPC server.
+func generateGRPCToken(securePath string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("error generating gRPC auth token: %v", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	tokenPath := filepath.Join(securePath, grpcTokenFileName)
+	if err := os.WriteFile(tokenPath, []byte(token), 0600); err != nil {
+		return "", fmt.Errorf("error writing gRPC auth token: %v", err)
+	}
+	if err := os.Chmod(tokenPath, 0600); err != nil {
+		return "", fmt.Errorf("error setting gRPC auth token permissions: %v", err)
+	}
+	return token, nil
+}
+
+// grpcAuthInterceptors checks every unary and streaming call against token,
+// presented as `authorization: Bearer <token>` metadata, rejecting anything
+// else with codes.Unauthenticated. Without this, the loopback TCP listener
+// above (the only control path on Windows, where there's no Unix socket
+// fallback) would let any local, unprivileged process call
+// ScheduleReboot/CancelReboot/TriggerPatchScan.
+func grpcAuthInterceptors(token string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authorized := func(ctx context.Context) error {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+		values := md.Get("authorization")
+		if len(values) != 1 || values[0] != "Bearer "+token {
+			return status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+		}
+		return nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorized(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorized(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
+
+// startGRPCServer serves NotifierService on loopback TCP and, on
+// non-Windows hosts, an additional Unix socket locked to 0600 so only the
+// owning user can reach it. Every call on either listener must present the
+// bearer token written by generateGRPCToken. Both listeners are stopped
+// when ctx is cancelled.
+func startGRPCServer(ctx context.Context, securePath string) error {
+	token, err := generateGRPCToken(securePath)
+	if err != nil {
+		return err
+	}
+	unaryAuth, streamAuth := grpcAuthInterceptors(token)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(unaryAuth), grpc.StreamInterceptor(streamAuth))
+	rpc.RegisterNotifierServiceServer(srv, &notifierGRPCServer{})
+
+	lis, err := net.Listen("tcp", grpcLoopbackAddr)
+	if err != nil {
+		return fmt.Errorf("error listening on loopback: %v", err)
+	}
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			debugLog("gRPC loopback server stopped:", err)
+		}
+	}()
+
+	if runtime.GOOS != "windows" {
+		sockPath := filepath.Join(securePath, "secops_notifier.sock")
+		os.Remove(sockPath) // stale socket left behind by a previous run
+		if unixLis, err := net.Listen("unix", sockPath); err != nil {
+			log.Printf("Error listening on Unix socket %s: %v", sockPath, err)
+		} else if err := os.Chmod(sockPath, 0600); err != nil {
+			log.Printf("Error setting permissions on Unix socket %s: %v", sockPath, err)
+		} else {
+			go func() {
+				if err := srv.Serve(unixLis); err != nil {
+					debugLog("gRPC Unix socket server stopped:", err)
+				}
+			}()
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	return nil
+}
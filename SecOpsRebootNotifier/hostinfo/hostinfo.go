@@ -0,0 +1,78 @@
+// Package hostinfo wraps gopsutil so the rest of the notifier doesn't have to
+// shell out for process and host introspection.
+package hostinfo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Distro describes the platform gopsutil detected, replacing the old
+// apt/yum/zypper probing done by shelling out to bash.
+type Distro struct {
+	Platform string
+	Family   string
+	Version  string
+}
+
+// Snapshot captures the host facts a server callback needs to decide whether
+// it's safe to force a reboot: how long the host has been up, who's logged
+// in, and whether a reboot is already pending.
+type Snapshot struct {
+	Platform      string
+	Family        string
+	Version       string
+	BootTime      time.Time
+	Uptime        time.Duration
+	Users         []string
+	RebootPending bool
+}
+
+// IsProcessRunning reports whether pid is alive, replacing the
+// tasklist/FindProcess+Signal(0) split that used to exist per OS.
+func IsProcessRunning(pid int) (bool, error) {
+	return process.PidExists(int32(pid))
+}
+
+// Info returns the host's platform, family, and version, replacing the
+// apt/yum/zypper shell probe previously used to identify the Linux distro.
+func Info() (Distro, error) {
+	platform, family, version, err := host.PlatformInformation()
+	if err != nil {
+		return Distro{}, fmt.Errorf("error getting platform information: %v", err)
+	}
+	return Distro{Platform: platform, Family: family, Version: version}, nil
+}
+
+// TakeSnapshot gathers uptime, boot time, and logged-in users from the host,
+// embedding the caller-supplied pending-reboot flag so the result can be
+// attached to any outbound server callback.
+func TakeSnapshot(rebootPending bool) (Snapshot, error) {
+	info, err := host.Info()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("error getting host info: %v", err)
+	}
+
+	users, err := host.Users()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("error getting logged-in users: %v", err)
+	}
+
+	usernames := make([]string, 0, len(users))
+	for _, u := range users {
+		usernames = append(usernames, u.User)
+	}
+
+	return Snapshot{
+		Platform:      info.Platform,
+		Family:        info.PlatformFamily,
+		Version:       info.PlatformVersion,
+		BootTime:      time.Unix(int64(info.BootTime), 0),
+		Uptime:        time.Duration(info.Uptime) * time.Second,
+		Users:         usernames,
+		RebootPending: rebootPending,
+	}, nil
+}
@@ -0,0 +1,110 @@
+// Package configstore turns the notifier's on-disk JSON config file into a
+// typed event stream. It replaces the once-a-second loadConfig/updateConfig
+// poll, which re-read and re-parsed the file whether or not it had changed
+// and raced with the same file's writers.
+package configstore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Event reports that the watched file's contents changed. Data holds the
+// bytes already read, so callers never need to re-open the file (and race
+// the next writer) just to act on the event.
+type Event struct {
+	Data []byte
+	Err  error
+}
+
+// Store watches a single file and emits an Event on the channel returned by
+// Events whenever its contents actually change.
+type Store struct {
+	path     string
+	watcher  *fsnotify.Watcher
+	events   chan Event
+	lastData []byte
+}
+
+// New starts watching path and returns the Store. The directory, not the
+// file itself, is watched so the atomic write-then-rename WriteAtomic
+// performs (and the rename other writers do) is still seen: many platforms
+// drop a file-level watch once its inode is replaced by a rename.
+func New(path string) (*Store, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("error creating config file watcher: %v", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("error watching config directory: %v", err)
+	}
+
+	s := &Store{path: path, watcher: watcher, events: make(chan Event, 1)}
+	go s.run()
+	return s, nil
+}
+
+func (s *Store) run() {
+	defer close(s.events)
+	base := filepath.Base(s.path)
+
+	for {
+		select {
+		case ev, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Base(ev.Name) != base {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			data, err := os.ReadFile(s.path)
+			if err != nil {
+				// The write-then-rename swap can briefly leave the file
+				// missing; the next event will carry the real contents.
+				continue
+			}
+			if bytes.Equal(data, s.lastData) {
+				continue
+			}
+			s.lastData = data
+			s.events <- Event{Data: data}
+
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			s.events <- Event{Err: err}
+		}
+	}
+}
+
+// Events returns the channel of config-change events. It is closed once
+// Close is called.
+func (s *Store) Events() <-chan Event { return s.events }
+
+// Close stops watching the file.
+func (s *Store) Close() error { return s.watcher.Close() }
+
+// WriteAtomic writes data to path using the same write-to-temp-then-rename
+// pattern every mutator in this notifier already followed by hand, so a
+// reader (including this package's own watcher) never observes a partial
+// write.
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return fmt.Errorf("error writing temp file: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error renaming temp file: %v", err)
+	}
+	return nil
+}
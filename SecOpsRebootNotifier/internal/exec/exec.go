@@ -0,0 +1,144 @@
+// Package exec runs the handful of OS commands the notifier needs to warn
+// logged-in users and trigger a reboot. It replaces the fmt.Sprintf-built
+// shell/AppleScript/PowerShell strings that used to carry CustomMessage text
+// straight into bash -c, osascript -e, and powershell -Command: every
+// implementation here passes arguments as argv arrays (or, on Windows, as
+// COM BSTRs) so an operator-supplied message can never break out into a new
+// command.
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SafeRunner executes the two commands a reboot warning needs — broadcasting
+// the message to logged-in users and performing the reboot itself — entirely
+// through argv arrays, never through an interpolated shell or AppleScript
+// string.
+type SafeRunner interface {
+	// Warn broadcasts msg to the host's logged-in users.
+	Warn(msg string) error
+	// Reboot restarts the host.
+	Reboot() error
+}
+
+// RebootScheduler reboots the host after delay, using whichever native
+// mechanism the OS provides. On Linux and macOS it first warns logged-in
+// users with msg; on Windows msg only becomes the reboot task's description
+// and no warning is shown. Callers use the same method regardless of
+// platform, so CustomMessage text never touches a shell or PowerShell
+// command line, and reaches AppleScript only through appleScriptLiteral.
+type RebootScheduler interface {
+	ScheduleReboot(ctx context.Context, delay time.Duration, msg string) error
+}
+
+// NewRebootScheduler builds the RebootScheduler for the current host.
+func NewRebootScheduler() (RebootScheduler, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return &linuxRebootScheduler{runner: linuxRunner{}}, nil
+	case "darwin":
+		return &darwinRebootScheduler{runner: darwinRunner{}}, nil
+	case "windows":
+		return &windowsRebootScheduler{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	}
+}
+
+// Notify shows a user-facing notification without scheduling a reboot. On
+// platforms with no native notification mechanism it is a no-op, mirroring
+// the old macDisplayNotification's own runtime.GOOS guard.
+func Notify(msg string) error {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	return darwinRunner{}.Warn(msg)
+}
+
+// linuxRunner implements SafeRunner on Linux: wall(1) for the broadcast and
+// shutdown(8) for the reboot, both invoked as argv arrays and, where
+// libseccomp is available, confined by runSandboxed to the syscalls those
+// tools need.
+type linuxRunner struct{}
+
+func (linuxRunner) Warn(msg string) error {
+	return runSandboxed(exec.Command("wall", msg))
+}
+
+func (linuxRunner) Reboot() error {
+	return runSandboxed(exec.Command("sudo", "shutdown", "-r", "now"))
+}
+
+// linuxRebootScheduler warns logged-in users immediately, then reboots after
+// delay — the same 2-minute warning window scheduleRebootNowTask used to
+// implement with `wall "..."; sleep 120; sudo reboot`.
+type linuxRebootScheduler struct {
+	runner SafeRunner
+}
+
+func (s *linuxRebootScheduler) ScheduleReboot(ctx context.Context, delay time.Duration, msg string) error {
+	if err := s.runner.Warn(msg); err != nil {
+		return fmt.Errorf("error broadcasting reboot warning: %v", err)
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.runner.Reboot()
+}
+
+// darwinRunner implements SafeRunner on macOS. AppleScript literals are
+// built with appleScriptLiteral (a concatenation of literal segments joined
+// by AppleScript's own `quote` constant) instead of a single-pass `\"`
+// replace, and osascript is invoked with the finished script as a single
+// argv element, never substituted into a larger shell command.
+type darwinRunner struct{}
+
+func (darwinRunner) Warn(msg string) error {
+	script := `display notification ` + appleScriptLiteral(msg) + ` with title ` + appleScriptLiteral("SecOps Notifier")
+	return exec.Command("/usr/bin/osascript", "-e", script).Run()
+}
+
+func (darwinRunner) Reboot() error {
+	return exec.Command("/sbin/shutdown", "-r", "now").Run()
+}
+
+// appleScriptLiteral renders s as an AppleScript expression assembled from
+// literal segments joined by `quote`, so a `"` embedded in s can't close the
+// surrounding AppleScript string the way a single-pass `\"` escape could.
+func appleScriptLiteral(s string) string {
+	segments := strings.Split(s, `"`)
+	for i, seg := range segments {
+		segments[i] = `"` + strings.ReplaceAll(seg, `\`, `\\`) + `"`
+	}
+	return strings.Join(segments, " & quote & ")
+}
+
+// darwinRebootScheduler notifies the logged-in user, waits out delay, then
+// reboots. The original scheduleRebootNowTask wrote this same two-step flow
+// to a generated bash script on disk; here it's just two SafeRunner calls.
+type darwinRebootScheduler struct {
+	runner SafeRunner
+}
+
+func (s *darwinRebootScheduler) ScheduleReboot(ctx context.Context, delay time.Duration, msg string) error {
+	if err := s.runner.Warn(msg); err != nil {
+		return fmt.Errorf("error displaying reboot warning: %v", err)
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// Best-effort heads-up that the reboot is starting now; a failure here
+	// shouldn't stop the reboot itself.
+	_ = s.runner.Warn("Rebooting now...")
+	return s.runner.Reboot()
+}
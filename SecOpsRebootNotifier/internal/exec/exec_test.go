@@ -0,0 +1,48 @@
+package exec
+
+import (
+	"strings"
+	"testing"
+)
+
+// joinedAppleScriptLiteral evaluates the `"a" & quote & "b"` expression
+// appleScriptLiteral builds, the same way osascript would, so the test
+// checks the literal actually reconstructs s rather than just eyeballing
+// its quoting.
+func joinedAppleScriptLiteral(s string) string {
+	segments := strings.Split(appleScriptLiteral(s), ` & quote & `)
+	for i, seg := range segments {
+		unquoted := strings.TrimSuffix(strings.TrimPrefix(seg, `"`), `"`)
+		segments[i] = strings.ReplaceAll(unquoted, `\\`, `\`)
+	}
+	return strings.Join(segments, `"`)
+}
+
+func TestAppleScriptLiteralRoundTrip(t *testing.T) {
+	cases := []string{
+		"",
+		"plain message",
+		`message with "quotes" in it`,
+		`message with \backslashes\`,
+		`"leading and trailing"`,
+		`mixed \" escape-looking sequence`,
+	}
+	for _, s := range cases {
+		if got := joinedAppleScriptLiteral(s); got != s {
+			t.Errorf("appleScriptLiteral(%q) round-trips to %q, want %q", s, got, s)
+		}
+	}
+}
+
+func TestAppleScriptLiteralCannotEscapeSurroundingString(t *testing.T) {
+	// A naive `"` + s + `"` build would let this break out of the
+	// surrounding `display notification "..."` string; appleScriptLiteral
+	// must keep every embedded quote inside its own quoted segment.
+	malicious := `" & do shell script "rm -rf /" & "`
+	literal := appleScriptLiteral(malicious)
+	for _, seg := range strings.Split(literal, ` & quote & `) {
+		if !strings.HasPrefix(seg, `"`) || !strings.HasSuffix(seg, `"`) {
+			t.Errorf("segment %q of %q is not a quoted AppleScript string literal", seg, literal)
+		}
+	}
+}
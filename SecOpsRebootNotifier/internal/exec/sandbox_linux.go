@@ -0,0 +1,122 @@
+//go:build linux && seccomp
+
+// Build with `-tags seccomp` to enable this file; it requires the
+// libseccomp-golang CGO binding, which in turn needs the libseccomp-dev
+// headers at build time. Without the tag, sandbox_linux_unconfined.go
+// provides the same runSandboxed signature as a plain, unconfined exec —
+// seccomp confinement is a hardening extra, not a build-time or runtime
+// requirement for the single most important action this binary performs.
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	seccomp "github.com/seccomp/libseccomp-golang"
+)
+
+// seccompChildEnv flags a re-exec'd child that should install the seccomp
+// filter on itself before becoming the real command, the same
+// fork-then-filter-then-exec pattern Mattermost's rpcplugin sandbox uses to
+// confine plugin subprocesses.
+const seccompChildEnv = "SECOPS_NOTIFIER_SECCOMP_CHILD"
+
+// allowedSyscalls covers what shutdown, wall, sleep, cp, and chmod need to
+// run to completion, plus what sudo and a typical PAM stack (pam_unix,
+// pam_systemd, NSS name lookups) need to authenticate and exec the child:
+// credential/capability calls, NSS's socket-based lookups, and the
+// futex/ioctl/epoll machinery glibc and systemd's sd-bus client use. Verify
+// this list against an strace of the actual `sudo shutdown`/`wall`
+// invocations on your distro's PAM configuration before relying on it —
+// a missing syscall here fails the child silently (EPERM, not a crash).
+var allowedSyscalls = []string{
+	"execve", "exit", "exit_group", "brk", "mmap", "mmap2", "mprotect", "munmap",
+	"madvise", "access", "faccessat", "faccessat2", "openat", "open", "close",
+	"read", "pread64", "write", "fstat", "newfstatat", "stat", "lstat",
+	"statx", "lseek", "getdents64", "reboot", "sync", "nanosleep",
+	"clock_nanosleep", "clock_gettime", "gettimeofday", "rename", "renameat",
+	"renameat2", "unlink", "unlinkat", "chmod", "fchmod", "fchmodat", "chown",
+	"fchown", "rt_sigaction", "rt_sigprocmask", "rt_sigreturn", "sigaltstack",
+	"arch_prctl", "prctl", "set_tid_address", "set_robust_list", "prlimit64",
+	"getrandom", "getuid", "geteuid", "getgid", "getegid", "getresuid",
+	"getresgid", "setuid", "setgid", "setresuid", "setresgid", "setgroups",
+	"capget", "capset", "getpid", "gettid", "getppid", "uname", "sysinfo",
+	"socket", "connect", "sendto", "recvfrom", "sendmsg", "recvmsg",
+	"getsockname", "ioctl", "fcntl", "dup", "dup2", "dup3", "pipe", "pipe2",
+	"poll", "ppoll", "select", "epoll_create1", "epoll_ctl", "epoll_wait",
+	"futex", "clone", "clone3", "wait4", "kill", "tgkill", "umask", "chdir",
+	"getcwd", "readlink", "readlinkat", "alarm", "rseq",
+}
+
+func init() {
+	// If we were re-exec'd to install the filter on ourselves, do that and
+	// then become the real command; this never returns to main().
+	if os.Getenv(seccompChildEnv) != "1" {
+		return
+	}
+
+	args := os.Args[1:]
+	if len(args) == 0 {
+		os.Exit(1)
+	}
+	if err := installSeccompFilter(); err != nil {
+		fmt.Fprintf(os.Stderr, "seccomp: %v\n", err)
+		os.Exit(1)
+	}
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		os.Exit(1)
+	}
+	_ = syscall.Exec(path, args, os.Environ())
+	os.Exit(1) // only reached if Exec itself failed
+}
+
+// installSeccompFilter loads an allowlist-only filter (deny-by-errno for
+// anything not on the list) into the current process.
+func installSeccompFilter() error {
+	filter, err := seccomp.NewFilter(seccomp.ActErrno.SetReturnCode(1))
+	if err != nil {
+		return fmt.Errorf("error creating seccomp filter: %v", err)
+	}
+	defer filter.Release()
+
+	for _, name := range allowedSyscalls {
+		call, err := seccomp.GetSyscallFromName(name)
+		if err != nil {
+			continue // not present on this kernel/arch; skip rather than fail the whole filter
+		}
+		if err := filter.AddRule(call, seccomp.ActAllow); err != nil {
+			return fmt.Errorf("error allowing syscall %s: %v", name, err)
+		}
+	}
+	return filter.Load()
+}
+
+// seccompAvailable reports whether the linked libseccomp is version 2.3 or
+// newer; it does not probe the kernel.
+func seccompAvailable() bool {
+	major, minor, err := seccomp.GetLibraryVersion()
+	return err == nil && (major > 2 || (major == 2 && minor >= 3))
+}
+
+// runSandboxed re-execs the current binary so cmd runs under the seccomp
+// allowlist. It falls back to an unconfined run only when seccompAvailable
+// is false or the binary's own path can't be found; if the re-exec'd child
+// fails to load the filter, cmd is not run and an error is returned.
+func runSandboxed(cmd *exec.Cmd) error {
+	if !seccompAvailable() {
+		return cmd.Run()
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return cmd.Run()
+	}
+
+	wrapped := exec.Command(self, cmd.Args...)
+	wrapped.Stdout, wrapped.Stderr, wrapped.Stdin = cmd.Stdout, cmd.Stderr, cmd.Stdin
+	wrapped.Env = append(os.Environ(), seccompChildEnv+"=1")
+	return wrapped.Run()
+}
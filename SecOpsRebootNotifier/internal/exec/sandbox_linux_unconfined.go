@@ -0,0 +1,12 @@
+//go:build linux && !seccomp
+
+package exec
+
+import "os/exec"
+
+// runSandboxed runs cmd directly. Seccomp confinement (sandbox_linux.go) is
+// an opt-in hardening extra built with `-tags seccomp`; by default this
+// binary has no hard CGO/libseccomp-dev build dependency.
+func runSandboxed(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
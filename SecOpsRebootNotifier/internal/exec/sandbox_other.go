@@ -0,0 +1,10 @@
+//go:build !linux
+
+package exec
+
+import "os/exec"
+
+// runSandboxed runs cmd directly; seccomp confinement is Linux-only.
+func runSandboxed(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
@@ -0,0 +1,168 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	ole "github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// rebootNowTaskName names the one-shot Task Scheduler task windowsRebootScheduler
+// registers, distinct from the main package's TASK_NAME so an immediate
+// reboot never collides with a pending scheduleTask task.
+const rebootNowTaskName = "SecOpsNotifierRebootNowTask"
+
+// windowsRebootScheduler replaces the giant PowerShell one-liner
+// scheduleRebootNowTask used to build with the native Task Scheduler COM
+// API, so the task's name and description are passed as COM BSTRs rather
+// than interpolated into a -Command string.
+type windowsRebootScheduler struct{}
+
+func (s *windowsRebootScheduler) ScheduleReboot(ctx context.Context, delay time.Duration, msg string) error {
+	w, err := OpenWinTaskService()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	// msg is passed straight through as a BSTR property, never a command
+	// line, so it can't break out of a task action's argument string.
+	if err := w.RegisterOneShotTask(rebootNowTaskName, time.Now().Add(delay), "shutdown.exe", "/F /R /T 0", msg); err != nil {
+		return fmt.Errorf("error registering reboot task: %v", err)
+	}
+
+	if ctx.Err() != nil {
+		_ = w.DeleteTask(rebootNowTaskName)
+		return ctx.Err()
+	}
+	return nil
+}
+
+// WinTaskService holds an open COM session with the Windows Task Scheduler,
+// shared by windowsRebootScheduler here and scheduleAtWindows/
+// cancelScheduleWindows in the main package so the CoInitializeEx ->
+// Schedule.Service -> Connect -> GetFolder handshake (and its OS-thread
+// pinning) lives in exactly one place.
+type WinTaskService struct {
+	taskService *ole.IDispatch
+	folder      *ole.IDispatch
+}
+
+// OpenWinTaskService starts a Task Scheduler COM session and returns its root
+// folder. COM is initialized per OS thread (here in the multithreaded
+// apartment): CoInitializeEx and its matching CoUninitialize must run on the
+// same thread, so this pins the calling goroutine to its current thread
+// until the returned WinTaskService is closed.
+func OpenWinTaskService() (*WinTaskService, error) {
+	runtime.LockOSThread()
+
+	if err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED); err != nil {
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("error initializing COM: %v", err)
+	}
+
+	unknown, err := oleutil.CreateObject("Schedule.Service")
+	if err != nil {
+		ole.CoUninitialize()
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("error creating Schedule.Service: %v", err)
+	}
+	defer unknown.Release()
+
+	taskService, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		ole.CoUninitialize()
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("error querying ITaskService: %v", err)
+	}
+
+	if _, err := oleutil.CallMethod(taskService, "Connect"); err != nil {
+		taskService.Release()
+		ole.CoUninitialize()
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("error connecting to Task Scheduler: %v", err)
+	}
+
+	folderResult, err := oleutil.CallMethod(taskService, "GetFolder", `\`)
+	if err != nil {
+		taskService.Release()
+		ole.CoUninitialize()
+		runtime.UnlockOSThread()
+		return nil, fmt.Errorf("error getting root task folder: %v", err)
+	}
+
+	return &WinTaskService{taskService: taskService, folder: folderResult.ToIDispatch()}, nil
+}
+
+// Close releases the COM objects acquired by OpenWinTaskService, tears down
+// COM, and unpins the calling goroutine from its OS thread.
+func (w *WinTaskService) Close() {
+	w.folder.Release()
+	w.taskService.Release()
+	ole.CoUninitialize()
+	runtime.UnlockOSThread()
+}
+
+// RegisterOneShotTask defines and registers a one-shot, time-triggered task
+// named name that runs path (with args, if non-empty) at at with the given
+// description, replacing any existing task registered under that name.
+func (w *WinTaskService) RegisterOneShotTask(name string, at time.Time, path, args, description string) error {
+	taskDefResult, err := oleutil.CallMethod(w.taskService, "NewTask", 0)
+	if err != nil {
+		return fmt.Errorf("error creating task definition: %v", err)
+	}
+	taskDef := taskDefResult.ToIDispatch()
+	defer taskDef.Release()
+
+	regInfo := oleutil.MustGetProperty(taskDef, "RegistrationInfo").ToIDispatch()
+	defer regInfo.Release()
+	oleutil.PutProperty(regInfo, "Description", description)
+
+	triggers := oleutil.MustGetProperty(taskDef, "Triggers").ToIDispatch()
+	defer triggers.Release()
+	triggerResult, err := oleutil.CallMethod(triggers, "Create", 1 /* TASK_TRIGGER_TIME */)
+	if err != nil {
+		return fmt.Errorf("error creating time trigger: %v", err)
+	}
+	trigger := triggerResult.ToIDispatch()
+	defer trigger.Release()
+	oleutil.PutProperty(trigger, "StartBoundary", at.Format(time.RFC3339))
+
+	actions := oleutil.MustGetProperty(taskDef, "Actions").ToIDispatch()
+	defer actions.Release()
+	actionResult, err := oleutil.CallMethod(actions, "Create", 0 /* TASK_ACTION_EXEC */)
+	if err != nil {
+		return fmt.Errorf("error creating exec action: %v", err)
+	}
+	action := actionResult.ToIDispatch()
+	defer action.Release()
+	oleutil.PutProperty(action, "Path", path)
+	if args != "" {
+		oleutil.PutProperty(action, "Arguments", args)
+	}
+
+	principal := oleutil.MustGetProperty(taskDef, "Principal").ToIDispatch()
+	defer principal.Release()
+	oleutil.PutProperty(principal, "RunLevel", 1 /* TASK_RUNLEVEL_HIGHEST */)
+
+	const (
+		taskCreateOrUpdate      = 6
+		taskLogonInteractiveTok = 3
+	)
+	if _, err := oleutil.CallMethod(w.folder, "RegisterTaskDefinition", name, taskDef,
+		taskCreateOrUpdate, nil, nil, taskLogonInteractiveTok); err != nil {
+		return fmt.Errorf("error registering task %q: %v", name, err)
+	}
+	return nil
+}
+
+// DeleteTask removes the named task. Absence of a matching task is the
+// common case for cancellation callers, not a failure; they can log the
+// error non-fatally rather than surface it.
+func (w *WinTaskService) DeleteTask(name string) error {
+	_, err := oleutil.CallMethod(w.folder, "DeleteTask", name, 0)
+	return err
+}
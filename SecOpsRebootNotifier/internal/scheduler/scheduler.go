@@ -0,0 +1,83 @@
+// Package scheduler drives the reboot-required workflow from a select over
+// config-change state and a cancellation context. It replaces the
+// `for { ...; time.Sleep(1 * time.Second) }` loop that used to re-evaluate
+// scheduling once a second regardless of whether anything had changed.
+package scheduler
+
+import "context"
+
+// State is the subset of notifier config the Scheduler acts on.
+type State struct {
+	TaskScheduled bool
+	RebootNow     bool
+	ScheduledTime string
+}
+
+// Handlers are the actions the Scheduler triggers. main supplies
+// implementations backed by scheduleTask/scheduleRebootNowTask, so this
+// package stays free of any notifier-specific config or OS knowledge.
+type Handlers struct {
+	// ScheduleTask arranges for the notifier to run at scheduledTime (or
+	// immediately, if scheduledTime is empty).
+	ScheduleTask func(scheduledTime string)
+	// ScheduleRebootNow starts the immediate-reboot warning/delay/reboot flow.
+	ScheduleRebootNow func()
+}
+
+// Scheduler reacts to State pushed in via Update, driving Handlers off a
+// select over the update channel and ctx cancellation instead of polling.
+type Scheduler struct {
+	handlers Handlers
+	updates  chan State
+}
+
+// New builds a Scheduler that calls h in response to state pushed via Update.
+func New(h Handlers) *Scheduler {
+	return &Scheduler{handlers: h, updates: make(chan State, 1)}
+}
+
+// Update pushes the latest state into the Scheduler. It never blocks: if
+// Run hasn't consumed the previous value yet, Update replaces it, since only
+// the most recent state matters.
+func (s *Scheduler) Update(st State) {
+	select {
+	case s.updates <- st:
+		return
+	default:
+	}
+	select {
+	case <-s.updates:
+	default:
+	}
+	select {
+	case s.updates <- st:
+	default:
+	}
+}
+
+// Run processes state updates until ctx is cancelled. The scheduling handoff
+// is immediate: ScheduleTask is always called with the real ScheduledTime so
+// ServiceController.ScheduleAt registers it with the OS-native mechanism
+// (systemd timer / launchd plist / Task Scheduler trigger). That's what
+// keeps the reboot armed if this process dies before the target time
+// arrives — Run itself holds no timer for the actual handoff, since an
+// in-process timer for that would mean a crashed or restarted daemon loses
+// the reboot entirely.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case st := <-s.updates:
+			if st.RebootNow {
+				s.handlers.ScheduleRebootNow()
+				continue
+			}
+			if st.TaskScheduled {
+				continue
+			}
+			s.handlers.ScheduleTask(st.ScheduledTime)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder captures Handlers calls so tests can assert on them without
+// touching any real OS scheduling mechanism.
+type recorder struct {
+	mu             sync.Mutex
+	scheduledTimes []string
+	rebootNowCalls int
+}
+
+func (r *recorder) handlers() Handlers {
+	return Handlers{
+		ScheduleTask: func(scheduledTime string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.scheduledTimes = append(r.scheduledTimes, scheduledTime)
+		},
+		ScheduleRebootNow: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.rebootNowCalls++
+		},
+	}
+}
+
+func (r *recorder) snapshot() ([]string, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.scheduledTimes...), r.rebootNowCalls
+}
+
+// waitFor polls until cond is true or the deadline passes, so tests don't
+// race the goroutine running Scheduler.Run.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("condition not met before deadline")
+}
+
+func TestSchedulerDispatchesFreshSchedule(t *testing.T) {
+	rec := &recorder{}
+	s := New(rec.handlers())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	s.Update(State{ScheduledTime: "2026-08-01T00:00:00Z"})
+
+	waitFor(t, func() bool {
+		times, _ := rec.snapshot()
+		return len(times) == 1
+	})
+	times, rebootNow := rec.snapshot()
+	if times[0] != "2026-08-01T00:00:00Z" {
+		t.Errorf("ScheduleTask called with %q, want the real scheduled time", times[0])
+	}
+	if rebootNow != 0 {
+		t.Errorf("ScheduleRebootNow called %d times, want 0", rebootNow)
+	}
+}
+
+func TestSchedulerSkipsAlreadyScheduledTask(t *testing.T) {
+	rec := &recorder{}
+	s := New(rec.handlers())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	s.Update(State{TaskScheduled: true, ScheduledTime: "2026-08-01T00:00:00Z"})
+	// Give Run a chance to process the update; there's nothing to wait on
+	// since the expected outcome is that nothing happens.
+	time.Sleep(20 * time.Millisecond)
+
+	times, rebootNow := rec.snapshot()
+	if len(times) != 0 {
+		t.Errorf("ScheduleTask called %v, want no calls for an already-scheduled task", times)
+	}
+	if rebootNow != 0 {
+		t.Errorf("ScheduleRebootNow called %d times, want 0", rebootNow)
+	}
+}
+
+func TestSchedulerRebootNowTakesPriority(t *testing.T) {
+	rec := &recorder{}
+	s := New(rec.handlers())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	s.Update(State{RebootNow: true, TaskScheduled: true, ScheduledTime: "2026-08-01T00:00:00Z"})
+
+	waitFor(t, func() bool {
+		_, rebootNow := rec.snapshot()
+		return rebootNow == 1
+	})
+	times, _ := rec.snapshot()
+	if len(times) != 0 {
+		t.Errorf("ScheduleTask called %v, want no calls when RebootNow is set", times)
+	}
+}
+
+func TestSchedulerStopsOnContextCancel(t *testing.T) {
+	rec := &recorder{}
+	s := New(rec.handlers())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after context cancellation")
+	}
+}
@@ -0,0 +1,125 @@
+// Package wslenv detects whether the notifier is running inside a WSL
+// (Windows Subsystem for Linux) guest and, when it is, lets Linux-side code
+// reach the actual Windows host across the /mnt/c mount — through reg.exe
+// for registry checks and powershell.exe for host-side actions. A WSL
+// guest's own reboot-required markers and `sudo reboot` only ever affect
+// the Linux userland; the host Windows install is a separate machine that
+// has to be checked and rebooted on its own side of the split, the same
+// guest/host split podman had to model when it added its WSL machine
+// backend.
+package wslenv
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	osReleasePath = "/proc/sys/kernel/osrelease"
+	versionPath   = "/proc/version"
+
+	regExePath        = `/mnt/c/Windows/System32/reg.exe`
+	powerShellExePath = `/mnt/c/Windows/System32/WindowsPowerShell/v1.0/powershell.exe`
+)
+
+// IsWSL reports whether the process is running inside a WSL distro, using
+// the three signals Microsoft documents for this: the WSL_DISTRO_NAME
+// environment variable wsl.exe sets for the guest, and "microsoft"/"wsl"
+// appearing in either /proc/sys/kernel/osrelease or /proc/version.
+func IsWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	if hasMicrosoftMarker(osReleasePath) {
+		return true
+	}
+	return hasMicrosoftMarker(versionPath)
+}
+
+func hasMicrosoftMarker(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	lower := strings.ToLower(string(data))
+	return strings.Contains(lower, "microsoft") || strings.Contains(lower, "wsl")
+}
+
+// HostRebootPending consults the Windows host's own registry for three of
+// the pending-reboot markers checkIfRebootRequiredWindows looks for (the
+// WindowsUpdate Services Pending key and the SCCM client are not checked), reaching
+// it through reg.exe across the /mnt/c mount rather than wsl.exe -e (which
+// would require a helper binary on the host). A pending host reboot is
+// what actually blocks patching on a WSL box, regardless of what the Linux
+// guest itself reports.
+func HostRebootPending() (bool, []string, error) {
+	type key struct {
+		path  string
+		value string
+	}
+	keys := []key{
+		{path: `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\RebootPending`},
+		{path: `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired`},
+		{path: `HKLM\SYSTEM\CurrentControlSet\Control\Session Manager`, value: "PendingFileRenameOperations"},
+	}
+
+	var reasons []string
+	var lastErr error
+	for _, k := range keys {
+		args := []string{"query", k.path}
+		if k.value != "" {
+			args = append(args, "/v", k.value)
+		}
+		output, err := exec.Command(regExePath, args...).CombinedOutput()
+		if err != nil {
+			// reg.exe exits non-zero when the key/value is absent, which is
+			// the expected "no marker here" case, not a failure worth
+			// reporting — only remember it in case every key errors out.
+			lastErr = err
+			continue
+		}
+		if strings.TrimSpace(string(output)) != "" {
+			reasons = append(reasons, "Windows host registry marker present: "+k.path)
+		}
+	}
+
+	if len(reasons) == 0 && lastErr != nil {
+		// All queries failed; distinguish "reg.exe itself is unreachable"
+		// from "checked and found nothing pending".
+		if _, statErr := os.Stat(regExePath); statErr != nil {
+			return false, nil, fmt.Errorf("error reaching Windows host reg.exe: %v", statErr)
+		}
+	}
+
+	return len(reasons) > 0, reasons, nil
+}
+
+// ScheduleHostReboot asks the Windows host to warn logged-in users and
+// restart itself after delay, via powershell.exe reached through /mnt/c.
+// msg is passed as a single-quoted PowerShell literal (built by
+// literal, which doubles embedded quotes) so it can never break out of the
+// surrounding -Command string.
+func ScheduleHostReboot(delay time.Duration, msg string) error {
+	command := fmt.Sprintf(
+		"msg * %s; Start-Sleep -Seconds %d; Restart-Computer -Force",
+		literal(msg), int(delay.Seconds()),
+	)
+	return exec.Command(powerShellExePath, "-Command", command).Run()
+}
+
+// literal renders s as a single-quoted PowerShell string literal, doubling
+// any embedded single quotes. PowerShell also ends a single-quoted string at
+// the typographic quotes U+2018-U+201B, so those are doubled too.
+func literal(s string) string {
+	escaper := strings.NewReplacer(
+		"'", "''",
+		"‘", "‘‘",
+		"’", "’’",
+		"‚", "‚‚",
+		"‛", "‛‛",
+	)
+	return "'" + escaper.Replace(s) + "'"
+}
@@ -0,0 +1,55 @@
+package wslenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHasMicrosoftMarker(t *testing.T) {
+	write := func(t *testing.T, contents string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "marker")
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("writing fixture: %v", err)
+		}
+		return path
+	}
+
+	cases := []struct {
+		name     string
+		contents string
+		want     bool
+	}{
+		{"microsoft mixed case", "Linux version 5.10.0 Microsoft Standard", true},
+		{"wsl lowercase", "4.4.0-19041-wsl2", true},
+		{"unrelated kernel release", "5.15.0-91-generic", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := hasMicrosoftMarker(write(t, c.contents)); got != c.want {
+				t.Errorf("hasMicrosoftMarker(%q) = %v, want %v", c.contents, got, c.want)
+			}
+		})
+	}
+
+	if hasMicrosoftMarker(filepath.Join(t.TempDir(), "does-not-exist")) {
+		t.Error("hasMicrosoftMarker(missing file) = true, want false")
+	}
+}
+
+func TestIsWSLRespectsDistroNameEnv(t *testing.T) {
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+	if !IsWSL() {
+		t.Error("IsWSL() = false with WSL_DISTRO_NAME set, want true")
+	}
+}
+
+func TestIsWSLFalseOutsideWSL(t *testing.T) {
+	t.Setenv("WSL_DISTRO_NAME", "")
+	// This test host's own /proc/version and osrelease are assumed not to
+	// carry a WSL marker, which holds for ordinary Linux CI runners.
+	if IsWSL() {
+		t.Skip("test host's /proc/version or osrelease carries a WSL marker")
+	}
+}
@@ -0,0 +1,181 @@
+// Hand-maintained stand-in for protoc-gen-go output: this build
+// environment has no protoc/protoc-gen-go, so the message types for
+// notifier.proto are written by hand instead of generated. Keep this in
+// sync with notifier.proto by hand until a real protoc run replaces it.
+
+package rpc
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+type ScheduleRebootRequest struct {
+	Message      string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	DelaySeconds int64  `protobuf:"varint,2,opt,name=delay_seconds,json=delaySeconds,proto3" json:"delay_seconds,omitempty"`
+}
+
+func (m *ScheduleRebootRequest) Reset()         { *m = ScheduleRebootRequest{} }
+func (m *ScheduleRebootRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleRebootRequest) ProtoMessage()    {}
+
+func (m *ScheduleRebootRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ScheduleRebootRequest) GetDelaySeconds() int64 {
+	if m != nil {
+		return m.DelaySeconds
+	}
+	return 0
+}
+
+type ScheduleRebootResponse struct {
+	Accepted bool   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Error    string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *ScheduleRebootResponse) Reset()         { *m = ScheduleRebootResponse{} }
+func (m *ScheduleRebootResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduleRebootResponse) ProtoMessage()    {}
+
+func (m *ScheduleRebootResponse) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *ScheduleRebootResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type CancelRebootRequest struct{}
+
+func (m *CancelRebootRequest) Reset()         { *m = CancelRebootRequest{} }
+func (m *CancelRebootRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelRebootRequest) ProtoMessage()    {}
+
+type CancelRebootResponse struct {
+	Accepted bool   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Error    string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *CancelRebootResponse) Reset()         { *m = CancelRebootResponse{} }
+func (m *CancelRebootResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelRebootResponse) ProtoMessage()    {}
+
+func (m *CancelRebootResponse) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *CancelRebootResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type TriggerPatchScanRequest struct{}
+
+func (m *TriggerPatchScanRequest) Reset()         { *m = TriggerPatchScanRequest{} }
+func (m *TriggerPatchScanRequest) String() string { return proto.CompactTextString(m) }
+func (*TriggerPatchScanRequest) ProtoMessage()    {}
+
+type TriggerPatchScanResponse struct {
+	Accepted bool   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Error    string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *TriggerPatchScanResponse) Reset()         { *m = TriggerPatchScanResponse{} }
+func (m *TriggerPatchScanResponse) String() string { return proto.CompactTextString(m) }
+func (*TriggerPatchScanResponse) ProtoMessage()    {}
+
+func (m *TriggerPatchScanResponse) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *TriggerPatchScanResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type GetStatusRequest struct{}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+
+type WatchStatusRequest struct{}
+
+func (m *WatchStatusRequest) Reset()         { *m = WatchStatusRequest{} }
+func (m *WatchStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchStatusRequest) ProtoMessage()    {}
+
+type StatusResponse struct {
+	RebootRequired   bool     `protobuf:"varint,1,opt,name=reboot_required,json=rebootRequired,proto3" json:"reboot_required,omitempty"`
+	Reasons          []string `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	Packages         []string `protobuf:"bytes,3,rep,name=packages,proto3" json:"packages,omitempty"`
+	RebootScheduled  bool     `protobuf:"varint,4,opt,name=reboot_scheduled,json=rebootScheduled,proto3" json:"reboot_scheduled,omitempty"`
+	ScheduledTime    string   `protobuf:"bytes,5,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`
+	RunningPatchTask bool     `protobuf:"varint,6,opt,name=running_patch_task,json=runningPatchTask,proto3" json:"running_patch_task,omitempty"`
+}
+
+func (m *StatusResponse) Reset()         { *m = StatusResponse{} }
+func (m *StatusResponse) String() string { return proto.CompactTextString(m) }
+func (*StatusResponse) ProtoMessage()    {}
+
+func (m *StatusResponse) GetRebootRequired() bool {
+	if m != nil {
+		return m.RebootRequired
+	}
+	return false
+}
+
+func (m *StatusResponse) GetReasons() []string {
+	if m != nil {
+		return m.Reasons
+	}
+	return nil
+}
+
+func (m *StatusResponse) GetPackages() []string {
+	if m != nil {
+		return m.Packages
+	}
+	return nil
+}
+
+func (m *StatusResponse) GetRebootScheduled() bool {
+	if m != nil {
+		return m.RebootScheduled
+	}
+	return false
+}
+
+func (m *StatusResponse) GetScheduledTime() string {
+	if m != nil {
+		return m.ScheduledTime
+	}
+	return ""
+}
+
+func (m *StatusResponse) GetRunningPatchTask() bool {
+	if m != nil {
+		return m.RunningPatchTask
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+// Hand-maintained stand-in for protoc-gen-go-grpc output: this build
+// environment has no protoc/protoc-gen-go-grpc, so the client/server
+// stubs for notifier.proto are written by hand instead of generated.
+// Keep this in sync with notifier.proto by hand until a real protoc run
+// replaces it.
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	NotifierService_ScheduleReboot_FullMethodName   = "/secops.notifier.v1.NotifierService/ScheduleReboot"
+	NotifierService_CancelReboot_FullMethodName     = "/secops.notifier.v1.NotifierService/CancelReboot"
+	NotifierService_TriggerPatchScan_FullMethodName = "/secops.notifier.v1.NotifierService/TriggerPatchScan"
+	NotifierService_GetStatus_FullMethodName        = "/secops.notifier.v1.NotifierService/GetStatus"
+	NotifierService_WatchStatus_FullMethodName      = "/secops.notifier.v1.NotifierService/WatchStatus"
+)
+
+// NotifierServiceClient is the client API for NotifierService.
+type NotifierServiceClient interface {
+	ScheduleReboot(ctx context.Context, in *ScheduleRebootRequest, opts ...grpc.CallOption) (*ScheduleRebootResponse, error)
+	CancelReboot(ctx context.Context, in *CancelRebootRequest, opts ...grpc.CallOption) (*CancelRebootResponse, error)
+	TriggerPatchScan(ctx context.Context, in *TriggerPatchScanRequest, opts ...grpc.CallOption) (*TriggerPatchScanResponse, error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (NotifierService_WatchStatusClient, error)
+}
+
+type notifierServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNotifierServiceClient(cc grpc.ClientConnInterface) NotifierServiceClient {
+	return &notifierServiceClient{cc}
+}
+
+func (c *notifierServiceClient) ScheduleReboot(ctx context.Context, in *ScheduleRebootRequest, opts ...grpc.CallOption) (*ScheduleRebootResponse, error) {
+	out := new(ScheduleRebootResponse)
+	if err := c.cc.Invoke(ctx, NotifierService_ScheduleReboot_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notifierServiceClient) CancelReboot(ctx context.Context, in *CancelRebootRequest, opts ...grpc.CallOption) (*CancelRebootResponse, error) {
+	out := new(CancelRebootResponse)
+	if err := c.cc.Invoke(ctx, NotifierService_CancelReboot_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notifierServiceClient) TriggerPatchScan(ctx context.Context, in *TriggerPatchScanRequest, opts ...grpc.CallOption) (*TriggerPatchScanResponse, error) {
+	out := new(TriggerPatchScanResponse)
+	if err := c.cc.Invoke(ctx, NotifierService_TriggerPatchScan_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notifierServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	if err := c.cc.Invoke(ctx, NotifierService_GetStatus_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notifierServiceClient) WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (NotifierService_WatchStatusClient, error) {
+	stream, err := c.cc.(*grpc.ClientConn).NewStream(ctx, &NotifierService_ServiceDesc.Streams[0], NotifierService_WatchStatus_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &notifierServiceWatchStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// NotifierService_WatchStatusClient is the streaming response for WatchStatus.
+type NotifierService_WatchStatusClient interface {
+	Recv() (*StatusResponse, error)
+	grpc.ClientStream
+}
+
+type notifierServiceWatchStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *notifierServiceWatchStatusClient) Recv() (*StatusResponse, error) {
+	m := new(StatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NotifierServiceServer is the server API for NotifierService.
+type NotifierServiceServer interface {
+	ScheduleReboot(context.Context, *ScheduleRebootRequest) (*ScheduleRebootResponse, error)
+	CancelReboot(context.Context, *CancelRebootRequest) (*CancelRebootResponse, error)
+	TriggerPatchScan(context.Context, *TriggerPatchScanRequest) (*TriggerPatchScanResponse, error)
+	GetStatus(context.Context, *GetStatusRequest) (*StatusResponse, error)
+	WatchStatus(*WatchStatusRequest, NotifierService_WatchStatusServer) error
+}
+
+// UnimplementedNotifierServiceServer can be embedded to satisfy
+// NotifierServiceServer for servers that only implement a subset of methods.
+type UnimplementedNotifierServiceServer struct{}
+
+func (UnimplementedNotifierServiceServer) ScheduleReboot(context.Context, *ScheduleRebootRequest) (*ScheduleRebootResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ScheduleReboot not implemented")
+}
+func (UnimplementedNotifierServiceServer) CancelReboot(context.Context, *CancelRebootRequest) (*CancelRebootResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelReboot not implemented")
+}
+func (UnimplementedNotifierServiceServer) TriggerPatchScan(context.Context, *TriggerPatchScanRequest) (*TriggerPatchScanResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TriggerPatchScan not implemented")
+}
+func (UnimplementedNotifierServiceServer) GetStatus(context.Context, *GetStatusRequest) (*StatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedNotifierServiceServer) WatchStatus(*WatchStatusRequest, NotifierService_WatchStatusServer) error {
+	return status.Error(codes.Unimplemented, "method WatchStatus not implemented")
+}
+
+// NotifierService_WatchStatusServer is the streaming request for WatchStatus.
+type NotifierService_WatchStatusServer interface {
+	Send(*StatusResponse) error
+	grpc.ServerStream
+}
+
+type notifierServiceWatchStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *notifierServiceWatchStatusServer) Send(m *StatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func RegisterNotifierServiceServer(s grpc.ServiceRegistrar, srv NotifierServiceServer) {
+	s.RegisterService(&NotifierService_ServiceDesc, srv)
+}
+
+func _NotifierService_ScheduleReboot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRebootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotifierServiceServer).ScheduleReboot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: NotifierService_ScheduleReboot_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotifierServiceServer).ScheduleReboot(ctx, req.(*ScheduleRebootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotifierService_CancelReboot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRebootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotifierServiceServer).CancelReboot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: NotifierService_CancelReboot_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotifierServiceServer).CancelReboot(ctx, req.(*CancelRebootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotifierService_TriggerPatchScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerPatchScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotifierServiceServer).TriggerPatchScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: NotifierService_TriggerPatchScan_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotifierServiceServer).TriggerPatchScan(ctx, req.(*TriggerPatchScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotifierService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotifierServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: NotifierService_GetStatus_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotifierServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotifierService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotifierServiceServer).WatchStatus(m, &notifierServiceWatchStatusServer{stream})
+}
+
+// NotifierService_ServiceDesc is the grpc.ServiceDesc for NotifierService.
+var NotifierService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "secops.notifier.v1.NotifierService",
+	HandlerType: (*NotifierServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "ScheduleReboot", Handler: _NotifierService_ScheduleReboot_Handler},
+		{MethodName: "CancelReboot", Handler: _NotifierService_CancelReboot_Handler},
+		{MethodName: "TriggerPatchScan", Handler: _NotifierService_TriggerPatchScan_Handler},
+		{MethodName: "GetStatus", Handler: _NotifierService_GetStatus_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _NotifierService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "notifier.proto",
+}
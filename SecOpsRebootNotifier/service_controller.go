@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kardianos/service"
+	safeexec "github.com/satyam235/macos-notifier/SecOpsRebootNotifier/internal/exec"
+)
+
+// launchdLabel is the identifier used for the macOS LaunchDaemon plist.
+const launchdLabel = "com.secops.notifier.task"
+
+// ServiceController is the single entry point for installing, starting,
+// stopping, and scheduling the notifier across Windows, Linux, and macOS. It
+// replaces the hand-rolled PowerShell/bash/pkill call sites that used to be
+// spread across stopAndRemoveService, scheduleTask, and deleteScheduledTask.
+type ServiceController interface {
+	Install() error
+	Uninstall() error
+	Start() error
+	Stop() error
+	Status() (service.Status, error)
+	ScheduleAt(at time.Time) error
+	CancelSchedule() error
+}
+
+// notifierProgram adapts the notifier's lifecycle to kardianos/service's
+// Program interface. The actual work happens in main's reboot-required
+// branch; this just satisfies the interface the service manager talks to.
+type notifierProgram struct{}
+
+func (p *notifierProgram) Start(s service.Service) error { return nil }
+func (p *notifierProgram) Stop(s service.Service) error  { return nil }
+
+// serviceController is the concrete ServiceController, backed by
+// github.com/kardianos/service for Install/Uninstall/Start/Stop/Status, with
+// OS-native scheduling for ScheduleAt/CancelSchedule.
+type serviceController struct {
+	svc service.Service
+}
+
+var (
+	globalServiceController     ServiceController
+	globalServiceControllerErr  error
+	globalServiceControllerOnce sync.Once
+)
+
+// getServiceController returns the process-wide ServiceController, building
+// it on first use.
+func getServiceController() (ServiceController, error) {
+	globalServiceControllerOnce.Do(func() {
+		globalServiceController, globalServiceControllerErr = newServiceController()
+	})
+	return globalServiceController, globalServiceControllerErr
+}
+
+// newServiceController builds the ServiceController for the current host.
+func newServiceController() (ServiceController, error) {
+	svcConfig := &service.Config{
+		Name:        SECOPS_NOTIFIER_SERVICE,
+		DisplayName: "SecOps Reboot Notifier",
+		Description: "Notifies users of pending reboots and schedules them on behalf of SecOps patch management.",
+	}
+
+	svc, err := service.New(&notifierProgram{}, svcConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating service: %v", err)
+	}
+
+	return &serviceController{svc: svc}, nil
+}
+
+func (c *serviceController) Install() error   { return c.svc.Install() }
+func (c *serviceController) Uninstall() error { return c.svc.Uninstall() }
+func (c *serviceController) Start() error     { return c.svc.Start() }
+func (c *serviceController) Stop() error      { return c.svc.Stop() }
+func (c *serviceController) Status() (service.Status, error) {
+	return c.svc.Status()
+}
+
+// ScheduleAt arranges for the notifier binary to run at the given time, using
+// the most native scheduling mechanism available on the host OS.
+func (c *serviceController) ScheduleAt(at time.Time) error {
+	switch runtime.GOOS {
+	case "windows":
+		return scheduleAtWindows(at)
+	case "linux":
+		return scheduleAtLinux(at)
+	case "darwin":
+		return scheduleAtDarwin(at)
+	default:
+		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	}
+}
+
+// CancelSchedule removes any pending schedule created by ScheduleAt.
+func (c *serviceController) CancelSchedule() error {
+	switch runtime.GOOS {
+	case "windows":
+		return cancelScheduleWindows()
+	case "linux":
+		return cancelScheduleLinux()
+	case "darwin":
+		return cancelScheduleDarwin()
+	default:
+		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	}
+}
+
+// scheduleAtWindows registers a one-shot Task Scheduler task via the COM API
+// instead of shelling out to Register-ScheduledTask. The COM handshake itself
+// lives in safeexec.WinTaskService, shared with windowsRebootScheduler's
+// immediate-reboot task.
+func scheduleAtWindows(at time.Time) error {
+	w, err := safeexec.OpenWinTaskService()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := w.RegisterOneShotTask(TASK_NAME, at, SECOPS_NOTIFIER_FILE_PATH, "", "SecOps Reboot Notifier"); err != nil {
+		return fmt.Errorf("error registering task: %v", err)
+	}
+	return nil
+}
+
+// cancelScheduleWindows deletes the task created by scheduleAtWindows.
+func cancelScheduleWindows() error {
+	w, err := safeexec.OpenWinTaskService()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := w.DeleteTask(TASK_NAME); err != nil {
+		// Not having a task to delete is the common case, not a failure.
+		debugLog("DeleteTask (non-fatal):", err)
+	}
+	return nil
+}
+
+// scheduleAtLinux prefers a systemd-run transient timer, falling back to
+// at(1) when systemctl is absent.
+func scheduleAtLinux(at time.Time) error {
+	if _, err := exec.LookPath("systemctl"); err == nil {
+		onCalendar := at.Format("2006-01-02 15:04:05")
+		cmd := exec.Command("systemd-run", "--unit="+TASK_NAME, "--on-calendar="+onCalendar, SECOPS_NOTIFIER_FILE_PATH)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("error scheduling systemd-run timer: %v, output: %s", err, string(out))
+		}
+		return nil
+	}
+	return scheduleAtLinuxSysV(at)
+}
+
+// cancelScheduleLinux tears down whichever scheduling mechanism scheduleAtLinux used.
+func cancelScheduleLinux() error {
+	if _, err := exec.LookPath("systemctl"); err == nil {
+		_ = exec.Command("systemctl", "stop", TASK_NAME+".timer").Run()
+		_ = exec.Command("systemctl", "stop", TASK_NAME+".service").Run()
+		return nil
+	}
+	return cancelScheduleLinuxSysV()
+}
+
+// scheduleAtLinuxSysV is the fallback for hosts without systemd: at(1), the
+// original SysV-era deferred-execution mechanism.
+func scheduleAtLinuxSysV(at time.Time) error {
+	cmd := exec.Command("at", at.Format("15:04 2006-01-02"))
+	cmd.Stdin = strings.NewReader(SECOPS_NOTIFIER_FILE_PATH + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error scheduling at(1) job: %v, output: %s", err, string(out))
+	}
+	return nil
+}
+
+// cancelScheduleLinuxSysV removes any pending at(1) jobs for the notifier.
+func cancelScheduleLinuxSysV() error {
+	out, err := exec.Command("atq").Output()
+	if err != nil {
+		// No atq binary or no queue; nothing to cancel.
+		return nil
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		job, err := exec.Command("at", "-c", fields[0]).Output()
+		if err != nil || !strings.Contains(string(job), SECOPS_NOTIFIER_FILE_PATH) {
+			continue
+		}
+		_ = exec.Command("atrm", fields[0]).Run()
+	}
+	return nil
+}
+
+// scheduleAtDarwin installs a launchd plist under /Library/LaunchDaemons
+// instead of writing a sleeping bash script.
+func scheduleAtDarwin(at time.Time) error {
+	plistPath := filepath.Join("/Library/LaunchDaemons", launchdLabel+".plist")
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+	</array>
+	<key>StartCalendarInterval</key>
+	<dict>
+		<key>Year</key><integer>%d</integer>
+		<key>Month</key><integer>%d</integer>
+		<key>Day</key><integer>%d</integer>
+		<key>Hour</key><integer>%d</integer>
+		<key>Minute</key><integer>%d</integer>
+	</dict>
+</dict>
+</plist>
+`, launchdLabel, SECOPS_NOTIFIER_FILE_PATH, at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute())
+
+	if err := os.WriteFile(plistPath, []byte(plist), 0644); err != nil {
+		return fmt.Errorf("error writing launchd plist: %v", err)
+	}
+
+	if out, err := exec.Command("launchctl", "load", plistPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("error loading launchd job: %v, output: %s", err, string(out))
+	}
+	return nil
+}
+
+// cancelScheduleDarwin unloads and removes the launchd plist installed by
+// scheduleAtDarwin.
+func cancelScheduleDarwin() error {
+	plistPath := filepath.Join("/Library/LaunchDaemons", launchdLabel+".plist")
+	_ = exec.Command("launchctl", "unload", plistPath).Run()
+	if err := os.Remove(plistPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing launchd plist: %v", err)
+	}
+	return nil
+}